@@ -0,0 +1,111 @@
+// Package atom parses Atom 1.0 (http://www.w3.org/2005/Atom) and Atom 0.3
+// (http://purl.org/atom/ns#) feeds into the shared feed model.
+package atom
+
+import (
+	"encoding/xml"
+
+	"reader/date"
+	"reader/feed"
+	"reader/sanitizer"
+)
+
+type link struct {
+	XMLName  xml.Name `xml:"link"`
+	Href     string   `xml:"href,attr"`
+	Rel      string   `xml:"rel,attr"`
+	Chardata string   `xml:",chardata"`
+}
+
+// entry covers both the 1.0 (published/updated) and 0.3 (issued/modified)
+// element names, since the two schemas only differ there.
+type entry struct {
+	XMLName   xml.Name `xml:"entry"`
+	Title     string   `xml:"title"`
+	Id        string   `xml:"id"`
+	Published string   `xml:"published"`
+	Issued    string   `xml:"issued"`
+	Updated   string   `xml:"updated"`
+	Modified  string   `xml:"modified"`
+	Links     []link   `xml:"link"`
+	Content   string   `xml:"content"`
+	Summary   string   `xml:"summary"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name `xml:"feed"`
+	Title    string   `xml:"title"`
+	Subtitle string   `xml:"subtitle"`
+	Tagline  string   `xml:"tagline"`
+	Id       string   `xml:"id"`
+	Links    []link   `xml:"link"`
+	Entries  []entry  `xml:"entry"`
+}
+
+// Parse decodes an Atom document (1.0 or 0.3) already positioned at its
+// opening <feed> token and normalizes it into the shared feed model.
+func Parse(decoder *xml.Decoder, start xml.StartElement) (*feed.Feed, error) {
+	var parsed atomFeed
+	if err := decoder.DecodeElement(&parsed, &start); err != nil {
+		return nil, err
+	}
+
+	var result feed.Feed
+	result.Title = parsed.Title
+	if parsed.Subtitle != "" {
+		result.Description = parsed.Subtitle
+	} else {
+		result.Description = parsed.Tagline
+	}
+	result.Id = parsed.Id
+	for _, l := range parsed.Links {
+		if l.Rel == "self" {
+			result.Link = l.Href
+			break
+		}
+	}
+	if result.Link == "" {
+		for _, l := range parsed.Links {
+			if l.Rel == "alternate" || l.Rel == "" {
+				result.Link = l.Href
+				break
+			}
+		}
+	}
+
+	for _, e := range parsed.Entries {
+		var en feed.Entry
+		en.Title = sanitizer.PlainText(e.Title)
+		en.Id = e.Id
+		for _, l := range e.Links {
+			if l.Rel == "alternate" || l.Rel == "" {
+				en.Link = l.Href
+				break
+			}
+		}
+
+		content := e.Content
+		if content == "" {
+			content = e.Summary
+		}
+		if content != "" {
+			en.Content = sanitizer.Sanitize(feed.EntryBase(en.Link, result.Link), content)
+		}
+
+		published := e.Published
+		if published == "" {
+			published = e.Issued
+		}
+		updated := e.Updated
+		if updated == "" {
+			updated = e.Modified
+		}
+
+		en.Published = date.ParseOrNow(published)
+		en.Updated = date.ParseOrNow(updated)
+
+		result.Entries = append(result.Entries, en)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,89 @@
+package atom
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantTitle string
+		wantLink  string
+	}{
+		{
+			name: "atom 1.0",
+			input: `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Example Blog</title>
+	<subtitle>An example blog</subtitle>
+	<id>https://example.com/</id>
+	<link rel="alternate" href="https://example.com/"/>
+	<entry>
+		<title>First post</title>
+		<id>https://example.com/first</id>
+		<published>2006-01-02T15:04:05Z</published>
+		<updated>2006-01-02T15:04:05Z</updated>
+		<link rel="alternate" href="https://example.com/first"/>
+	</entry>
+</feed>`,
+			wantTitle: "Example Blog",
+			wantLink:  "https://example.com/",
+		},
+		{
+			name: "atom 0.3",
+			input: `<?xml version="1.0"?>
+<feed version="0.3" xmlns="http://purl.org/atom/ns#">
+	<title>Example Blog</title>
+	<tagline>An example blog</tagline>
+	<id>https://example.com/</id>
+	<link rel="alternate" href="https://example.com/"/>
+	<entry>
+		<title>First post</title>
+		<id>https://example.com/first</id>
+		<issued>2006-01-02T15:04:05Z</issued>
+		<modified>2006-01-02T15:04:05Z</modified>
+		<link rel="alternate" href="https://example.com/first"/>
+	</entry>
+</feed>`,
+			wantTitle: "Example Blog",
+			wantLink:  "https://example.com/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := xml.NewDecoder(strings.NewReader(tt.input))
+
+			var start xml.StartElement
+			for start.Name.Local == "" {
+				token, err := decoder.Token()
+				if err != nil {
+					t.Fatalf("decoder.Token() error = %v", err)
+				}
+				if tok, ok := token.(xml.StartElement); ok {
+					start = tok
+				}
+			}
+
+			got, err := Parse(decoder, start)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			if got.Link != tt.wantLink {
+				t.Errorf("Link = %q, want %q", got.Link, tt.wantLink)
+			}
+			if len(got.Entries) != 1 {
+				t.Fatalf("len(Entries) = %d, want 1", len(got.Entries))
+			}
+			if got.Entries[0].Title != "First post" {
+				t.Errorf("Entries[0].Title = %q, want %q", got.Entries[0].Title, "First post")
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Package date parses the wide range of date formats found in the wild
+// across RSS and Atom feeds into time.Time values.
+package date
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// layouts is tried in order. It covers the formats mandated by the RSS and
+// Atom specs plus the common deviations feeds actually produce in practice.
+var layouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339Nano,
+	time.RFC3339,
+	time.ANSIC,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"02 Jan 2006 15:04:05 MST",
+	"02 Jan 2006 15:04:05 -0700",
+	"02 Jan 06 15:04:05 MST",
+	"02 Jan 06 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 06 15:04:05 MST",
+	"2 Jan 06 15:04:05 -0700",
+	"Mon, _2 Jan 2006 15:04:05 MST",
+	"Mon, _2 Jan 2006 15:04:05 -0700",
+	"Mon, _2 Jan 06 15:04:05 MST",
+	"Mon, _2 Jan 06 15:04:05 -0700",
+}
+
+// nonStandardZones maps zone abbreviations Go's time package doesn't know
+// about (or resolves ambiguously) to a fixed offset, so we can substitute
+// them before parsing.
+var nonStandardZones = map[string]string{
+	"UT":  "+0000",
+	"GMT": "+0000",
+	"Z":   "+0000",
+	"EST": "-0500",
+	"EDT": "-0400",
+	"CST": "-0600",
+	"CDT": "-0500",
+	"MST": "-0700",
+	"MDT": "-0600",
+	"PST": "-0800",
+	"PDT": "-0700",
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+var weekdayPrefix = regexp.MustCompile(`^[A-Za-z]+,\s*`)
+
+// Parse parses a date found in an RSS or Atom feed, trying a broad list of
+// layouts and normalizing common deviations (extra whitespace, non-standard
+// zone abbreviations, a redundant weekday prefix) before giving up. It
+// returns an error rather than a zero-value fallback so callers can decide
+// how to handle an unparseable date.
+func Parse(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, fmt.Errorf("date: empty date")
+	}
+
+	raw = whitespace.ReplaceAllString(raw, " ")
+
+	// NOTE: A mapped-offset candidate is tried before the raw one, since
+	// time.Parse accepts an unrecognized zone abbreviation but silently
+	// assigns it a zero offset instead of failing.
+	var candidates []string
+	if mapped, ok := withMappedZone(raw); ok {
+		candidates = append(candidates, mapped, weekdayPrefix.ReplaceAllString(mapped, ""))
+	}
+	candidates = append(candidates, raw, weekdayPrefix.ReplaceAllString(raw, ""))
+
+	for _, candidate := range candidates {
+		for _, layout := range layouts {
+			if parsed, err := time.Parse(layout, candidate); err == nil {
+				return parsed, nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("date: failed to parse %q as a date", raw)
+}
+
+// ParseOrNow parses a date as Parse does, but logs and falls back to
+// time.Now() instead of returning an error. It's the fallback every
+// format-specific parser wants for a missing or unparseable published/updated
+// date, so they don't each have to decide what "now" means.
+func ParseOrNow(raw string) time.Time {
+	parsed, err := Parse(raw)
+	if err != nil {
+		log.Print(err)
+		return time.Now()
+	}
+
+	return parsed
+}
+
+// withMappedZone replaces a trailing non-standard zone abbreviation with its
+// fixed offset equivalent, so formats like RFC822Z can match.
+func withMappedZone(raw string) (string, bool) {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	last := fields[len(fields)-1]
+	offset, ok := nonStandardZones[last]
+	if !ok {
+		return "", false
+	}
+
+	fields[len(fields)-1] = offset
+	return strings.Join(fields, " "), true
+}
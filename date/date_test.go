@@ -0,0 +1,99 @@
+package date
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want time.Time
+	}{
+		{
+			name: "RFC1123Z",
+			raw:  "Mon, 02 Jan 2006 15:04:05 -0700",
+			want: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.FixedZone("", -7*60*60)),
+		},
+		{
+			name: "RFC1123 with named zone",
+			raw:  "Mon, 02 Jan 2006 15:04:05 MST",
+			want: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.FixedZone("MST", -7*60*60)),
+		},
+		{
+			name: "RFC822Z",
+			raw:  "02 Jan 06 15:04 -0700",
+			want: time.Date(2006, time.January, 2, 15, 4, 0, 0, time.FixedZone("", -7*60*60)),
+		},
+		{
+			name: "RFC3339 with fractional seconds",
+			raw:  "2006-01-02T15:04:05.999999999Z",
+			want: time.Date(2006, time.January, 2, 15, 4, 5, 999999999, time.UTC),
+		},
+		{
+			name: "RFC3339 without fractional seconds",
+			raw:  "2006-01-02T15:04:05Z",
+			want: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			name: "ISO 8601 without timezone",
+			raw:  "2006-01-02T15:04:05",
+			want: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			name: "space-separated date and time",
+			raw:  "2006-01-02 15:04:05",
+			want: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			name: "two digit year with day not zero-padded",
+			raw:  "2 Jan 06 15:04:05 MST",
+			want: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.FixedZone("MST", -7*60*60)),
+		},
+		{
+			name: "UT zone abbreviation",
+			raw:  "Mon, 02 Jan 2006 15:04:05 UT",
+			want: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			name: "extra internal whitespace",
+			raw:  "Mon,  02  Jan  2006  15:04:05  -0700",
+			want: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.FixedZone("", -7*60*60)),
+		},
+		{
+			name: "leading/trailing whitespace",
+			raw:  "  2006-01-02T15:04:05Z  ",
+			want: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.raw)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.raw, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"not a date",
+		"sometime next week",
+	}
+
+	for _, raw := range tests {
+		t.Run(raw, func(t *testing.T) {
+			if _, err := Parse(raw); err == nil {
+				t.Errorf("Parse(%q) error = nil, want an error", raw)
+			}
+		})
+	}
+}
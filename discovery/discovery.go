@@ -0,0 +1,117 @@
+// Package discovery finds feed links advertised on an HTML page, so callers
+// can be pointed at a site's homepage instead of its raw feed URL.
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"reader/fetcher"
+)
+
+// feedTypes are the <link type="..."> values that mark a feed alternate.
+var feedTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+}
+
+// DiscoveredFeed is a feed advertised by a <link rel="alternate"> element on
+// an HTML page.
+type DiscoveredFeed struct {
+	Title string
+	Type  string
+	URL   string
+}
+
+// Discover fetches pageURL through f and, if it serves HTML, scans it for
+// <link rel="alternate"> elements advertising an RSS, Atom, or JSON feed,
+// resolving relative hrefs against the page's URL. It returns an empty slice
+// (not an error) if the page isn't HTML or advertises no feeds. Fetching
+// through f gives discovery requests the same timeout, body size cap, and
+// per-host rate limit as any other feed fetch.
+//
+// Callers that already have the page's body (e.g. because they just
+// downloaded it for another reason) should call FeedLinks directly instead,
+// so the page isn't fetched twice.
+func Discover(ctx context.Context, f *fetcher.Fetcher, pageURL string) ([]DiscoveredFeed, error) {
+	resp, err := f.Fetch(ctx, fetcher.FeedRequest{URL: pageURL})
+	if err != nil {
+		return nil, err
+	}
+	if resp.NotModified || !strings.Contains(resp.ContentType, "text/html") {
+		return nil, nil
+	}
+
+	return FeedLinks(resp.Body, resp.FinalURL)
+}
+
+// FeedLinks scans an already-fetched HTML page for <link rel="alternate">
+// elements advertising an RSS, Atom, or JSON feed, resolving relative hrefs
+// against baseURL.
+func FeedLinks(body []byte, baseURL string) ([]DiscoveredFeed, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return walkFeedLinks(doc, base), nil
+}
+
+func walkFeedLinks(doc *html.Node, base *url.URL) []DiscoveredFeed {
+	var feeds []DiscoveredFeed
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "link" {
+			if feed, ok := linkFeed(n, base); ok {
+				feeds = append(feeds, feed)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return feeds
+}
+
+func linkFeed(n *html.Node, base *url.URL) (DiscoveredFeed, bool) {
+	var rel, typ, href, title string
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "rel":
+			rel = attr.Val
+		case "type":
+			typ = attr.Val
+		case "href":
+			href = attr.Val
+		case "title":
+			title = attr.Val
+		}
+	}
+
+	if rel != "alternate" || href == "" || !feedTypes[typ] {
+		return DiscoveredFeed{}, false
+	}
+
+	return DiscoveredFeed{Title: title, Type: typ, URL: resolve(base, href)}, true
+}
+
+func resolve(base *url.URL, href string) string {
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
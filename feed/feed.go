@@ -0,0 +1,50 @@
+// Package feed holds the internal representation that every format-specific
+// parser (RSS, RDF, Atom, JSON Feed) normalizes into.
+package feed
+
+import "time"
+
+// Media is a single media item attached to an entry, as found in Media RSS
+// <media:content>/<media:thumbnail> elements or podcast enclosures.
+type Media struct {
+	URL         string
+	Type        string
+	Medium      string
+	Width       int
+	Height      int
+	Duration    time.Duration
+	IsDefault   bool
+	IsThumbnail bool
+}
+
+// Entry is a single item/entry within a feed, normalized across formats.
+type Entry struct {
+	Title     string
+	Id        string
+	Link      string
+	Content   string
+	Published time.Time
+	Updated   time.Time
+	Authors   []string
+	Media     []Media
+}
+
+// Feed is the normalized representation of a parsed feed, regardless of the
+// underlying format (RSS 2.0/1.0, Atom 0.3/1.0, or JSON Feed).
+type Feed struct {
+	Title       string
+	Description string
+	Id          string
+	Link        string
+	Updated     time.Time
+	Entries     []Entry
+}
+
+// EntryBase picks the URL an entry's relative links should be resolved
+// against: the entry's own link if it has one, falling back to the feed's.
+func EntryBase(entryLink, feedLink string) string {
+	if entryLink != "" {
+		return entryLink
+	}
+	return feedLink
+}
@@ -0,0 +1,306 @@
+// Package fetcher concurrently retrieves feeds over HTTP, with conditional
+// GET caching (ETag/Last-Modified) persisted between runs and a simple
+// per-host rate limit so a single slow or misbehaving site can't monopolize
+// the worker pool.
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultConcurrency is used when New is given a concurrency of 0 or less.
+const DefaultConcurrency = 8
+
+// MaxBodySize is the most we'll read from any single feed response.
+const MaxBodySize = 10 << 20 // 10 MiB
+
+// MinHostInterval is the minimum time between two requests to the same host.
+const MinHostInterval = 500 * time.Millisecond
+
+// MaxRetries is the most times Fetch will retry a single request after a
+// 429/503 response before giving up.
+const MaxRetries = 5
+
+// MaxRetryAfter caps how long Fetch will honor a server-supplied Retry-After
+// value, so a misbehaving server can't wedge a worker indefinitely.
+const MaxRetryAfter = 60 * time.Second
+
+const userAgent = "reader/1.0 (+https://github.com/Multipacker/rss-reader)"
+
+// FeedRequest describes a single feed to fetch.
+type FeedRequest struct {
+	URL string
+}
+
+// FeedResponse is the result of fetching a single feed. NotModified is true
+// when the server responded 304, in which case Body and ContentType are
+// unset and the caller should keep using whatever it already has.
+type FeedResponse struct {
+	URL         string
+	FinalURL    string
+	Body        []byte
+	ContentType string
+	NotModified bool
+}
+
+// cacheEntry is the conditional-GET state we remember for a URL between runs.
+type cacheEntry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// Fetcher concurrently fetches feeds, bounded by a configurable worker pool.
+type Fetcher struct {
+	client      *http.Client
+	concurrency int
+	cachePath   string
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+
+	hostMu   sync.Mutex
+	hostNext map[string]time.Time
+}
+
+// New creates a Fetcher with the given worker concurrency (DefaultConcurrency
+// if <= 0), loading any previously persisted ETag/Last-Modified cache from
+// cachePath (ignored if empty or missing).
+func New(concurrency int, cachePath string) (*Fetcher, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	f := &Fetcher{
+		client:      &http.Client{Timeout: 30 * time.Second},
+		concurrency: concurrency,
+		cachePath:   cachePath,
+		cache:       make(map[string]cacheEntry),
+		hostNext:    make(map[string]time.Time),
+	}
+
+	if cachePath != "" {
+		if err := f.loadCache(); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+func (f *Fetcher) loadCache() error {
+	data, err := os.ReadFile(f.cachePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return json.Unmarshal(data, &f.cache)
+}
+
+// SaveCache persists the ETag/Last-Modified cache to disk. It is a no-op if
+// the Fetcher was created without a cachePath.
+func (f *Fetcher) SaveCache() error {
+	if f.cachePath == "" {
+		return nil
+	}
+
+	f.mu.Lock()
+	data, err := json.MarshalIndent(f.cache, "", "\t")
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(f.cachePath, data, 0o644)
+}
+
+// FetchAll fetches every request concurrently, bounded by the fetcher's
+// configured worker count, and returns one response per request in the same
+// order. A request that fails is logged and returns a nil response.
+func (f *Fetcher) FetchAll(ctx context.Context, requests []FeedRequest) []*FeedResponse {
+	responses := make([]*FeedResponse, len(requests))
+
+	sem := make(chan struct{}, f.concurrency)
+	var wg sync.WaitGroup
+
+	for i, req := range requests {
+		wg.Add(1)
+		go func(i int, req FeedRequest) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			resp, err := f.Fetch(ctx, req)
+			if err != nil {
+				log.Printf("fetcher: %s: %v", req.URL, err)
+				return
+			}
+			responses[i] = resp
+		}(i, req)
+	}
+
+	wg.Wait()
+	return responses
+}
+
+// Fetch retrieves a single feed, sending conditional GET headers from any
+// previous fetch of the same URL and recording the new ones on success.
+func (f *Fetcher) Fetch(ctx context.Context, req FeedRequest) (*FeedResponse, error) {
+	return f.fetch(ctx, req, 0)
+}
+
+func (f *Fetcher) fetch(ctx context.Context, req FeedRequest, attempt int) (*FeedResponse, error) {
+	if err := f.waitForHost(ctx, req.URL); err != nil {
+		return nil, err
+	}
+
+	cached := f.cacheFor(req.URL)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, req.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("User-Agent", userAgent)
+	if cached.ETag != "" {
+		httpReq.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cached.LastModified != "" {
+		httpReq.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+
+	resp, err := f.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if wait, ok := retryAfter(resp); ok && attempt < MaxRetries {
+			if wait > MaxRetryAfter {
+				wait = MaxRetryAfter
+			}
+			log.Printf("fetcher: %s: %s, retrying after %v (attempt %d/%d)", req.URL, resp.Status, wait, attempt+1, MaxRetries)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			return f.fetch(ctx, req, attempt+1)
+		}
+	}
+
+	finalURL := req.URL
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalURL = resp.Request.URL.String()
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		log.Printf("fetcher: %s: not modified", req.URL)
+		return &FeedResponse{URL: req.URL, FinalURL: finalURL, NotModified: true}, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetcher: %s: %s", req.URL, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxBodySize))
+	if err != nil {
+		return nil, err
+	}
+
+	f.updateCache(req.URL, cacheEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	})
+
+	log.Printf("fetcher: %s: %d, %d bytes", req.URL, resp.StatusCode, len(body))
+
+	return &FeedResponse{
+		URL:         req.URL,
+		FinalURL:    finalURL,
+		Body:        body,
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+func (f *Fetcher) cacheFor(rawURL string) cacheEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cache[rawURL]
+}
+
+func (f *Fetcher) updateCache(rawURL string, entry cacheEntry) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cache[rawURL] = entry
+}
+
+// waitForHost blocks until at least MinHostInterval has passed since the
+// last request to rawURL's host, so one fast-polling feed on a shared host
+// doesn't starve the others.
+func (f *Fetcher) waitForHost(ctx context.Context, rawURL string) error {
+	host := hostOf(rawURL)
+
+	f.hostMu.Lock()
+	next, ok := f.hostNext[host]
+	now := time.Now()
+	if !ok || now.After(next) {
+		f.hostNext[host] = now.Add(MinHostInterval)
+		f.hostMu.Unlock()
+		return nil
+	}
+	f.hostNext[host] = next.Add(MinHostInterval)
+	f.hostMu.Unlock()
+
+	select {
+	case <-time.After(next.Sub(now)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Host
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		return time.Until(when), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,114 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchConditionalGet(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte("<rss></rss>"))
+	}))
+	defer server.Close()
+
+	f, err := New(1, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	resp, err := f.Fetch(context.Background(), FeedRequest{URL: server.URL})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if resp.NotModified {
+		t.Fatal("Fetch() NotModified = true on first request, want false")
+	}
+	if string(resp.Body) != "<rss></rss>" {
+		t.Errorf("Body = %q, want %q", resp.Body, "<rss></rss>")
+	}
+
+	resp, err = f.Fetch(context.Background(), FeedRequest{URL: server.URL})
+	if err != nil {
+		t.Fatalf("second Fetch() error = %v", err)
+	}
+	if !resp.NotModified {
+		t.Fatal("Fetch() NotModified = false on second request, want true since the ETag matched")
+	}
+
+	if requests != 2 {
+		t.Errorf("server saw %d requests, want 2", requests)
+	}
+}
+
+func TestFetchAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<rss></rss>"))
+	}))
+	defer server.Close()
+
+	f, err := New(4, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	requests := []FeedRequest{{URL: server.URL}, {URL: server.URL}, {URL: server.URL}}
+	responses := f.FetchAll(context.Background(), requests)
+
+	if len(responses) != len(requests) {
+		t.Fatalf("len(responses) = %d, want %d", len(responses), len(requests))
+	}
+	for i, resp := range responses {
+		if resp == nil {
+			t.Errorf("responses[%d] = nil, want a response", i)
+		}
+	}
+}
+
+func TestFetchRetryAfterGivesUp(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	f, err := New(1, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := f.Fetch(context.Background(), FeedRequest{URL: server.URL}); err == nil {
+		t.Fatal("Fetch() error = nil, want an error once retries are exhausted")
+	}
+
+	if requests != MaxRetries+1 {
+		t.Errorf("server saw %d requests, want %d", requests, MaxRetries+1)
+	}
+}
+
+func TestFetchNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	f, err := New(1, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := f.Fetch(context.Background(), FeedRequest{URL: server.URL}); err == nil {
+		t.Fatal("Fetch() error = nil, want an error for a 404 response")
+	}
+}
@@ -0,0 +1,61 @@
+package json
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	f, err := os.Open("testdata/feed.json")
+	if err != nil {
+		t.Fatalf("os.Open() error = %v", err)
+	}
+	defer f.Close()
+
+	got, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got.Title != "Example Blog" {
+		t.Errorf("Title = %q, want %q", got.Title, "Example Blog")
+	}
+	if got.Link != "https://example.com/" {
+		t.Errorf("Link = %q, want %q", got.Link, "https://example.com/")
+	}
+	if got.Id != "https://example.com/feed.json" {
+		t.Errorf("Id = %q, want %q", got.Id, "https://example.com/feed.json")
+	}
+	if len(got.Entries) != 2 {
+		t.Fatalf("len(Entries) = %d, want 2", len(got.Entries))
+	}
+
+	first := got.Entries[0]
+	if first.Title != "First post" {
+		t.Errorf("Entries[0].Title = %q, want %q", first.Title, "First post")
+	}
+	if first.Content != "<p>Hello, world!</p>" {
+		t.Errorf("Entries[0].Content = %q, want the HTML content", first.Content)
+	}
+	if len(first.Authors) != 1 || first.Authors[0] != "Jane Doe" {
+		t.Errorf("Entries[0].Authors = %v, want [Jane Doe]", first.Authors)
+	}
+	if !first.Updated.After(first.Published) {
+		t.Errorf("Entries[0].Updated = %v, want it after Published = %v", first.Updated, first.Published)
+	}
+
+	second := got.Entries[1]
+	if second.Link != "https://example.com/second" {
+		t.Errorf("Entries[1].Link = %q, want the external_url fallback", second.Link)
+	}
+	want := "Plain text with &lt;script&gt;alert(1)&lt;/script&gt; &amp; more."
+	if second.Content != want {
+		t.Errorf("Entries[1].Content = %q, want the escaped plain text content %q", second.Content, want)
+	}
+	if len(second.Authors) != 1 || second.Authors[0] != "John Doe" {
+		t.Errorf("Entries[1].Authors = %v, want [John Doe]", second.Authors)
+	}
+	if !second.Updated.Equal(second.Published) {
+		t.Errorf("Entries[1].Updated = %v, want it to fall back to Published = %v", second.Updated, second.Published)
+	}
+}
@@ -0,0 +1,92 @@
+// Package parser sniffs a feed payload to determine its format and dispatches
+// to the matching format-specific subpackage (reader/rss, reader/rdf,
+// reader/atom, reader/json), returning the shared feed model either way.
+package parser
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"golang.org/x/net/html/charset"
+
+	"reader/atom"
+	"reader/feed"
+	"reader/json"
+	"reader/rdf"
+	"reader/rss"
+)
+
+// Feed and Entry are re-exported so callers that only need the parsed result
+// don't also have to import reader/feed.
+type Feed = feed.Feed
+type Entry = feed.Entry
+
+const (
+	nsRDF    = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	nsAtom10 = "http://www.w3.org/2005/Atom"
+	nsAtom03 = "http://purl.org/atom/ns#"
+)
+
+// ParseFeed sniffs r to detect its format and parses it into the shared feed
+// model. It distinguishes JSON Feed, RSS 2.0/0.9x, RSS 1.0/RDF, and Atom
+// 0.3/1.0 by peeking at the first non-whitespace byte and, for XML payloads,
+// inspecting the root element's name and namespace.
+func ParseFeed(r io.Reader) (*Feed, error) {
+	buffered := bufio.NewReader(r)
+
+	first, err := peekNonSpace(buffered)
+	if err != nil {
+		return nil, err
+	}
+
+	if first == '{' {
+		return json.Parse(buffered)
+	}
+
+	decoder := xml.NewDecoder(buffered)
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	// NOTE: Find the first start element to determine the kind of feed we
+	// have, including its namespace so we can tell RSS 1.0 and the two Atom
+	// generations apart.
+	var start xml.StartElement
+	for start.Name.Local == "" {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := token.(xml.StartElement); ok {
+			start = t
+		}
+	}
+
+	switch {
+	case start.Name.Local == "rss":
+		return rss.Parse(decoder, start)
+	case start.Name.Local == "RDF" && start.Name.Space == nsRDF:
+		return rdf.Parse(decoder, start)
+	case start.Name.Local == "feed" && (start.Name.Space == nsAtom10 || start.Name.Space == nsAtom03 || start.Name.Space == ""):
+		return atom.Parse(decoder, start)
+	default:
+		return nil, fmt.Errorf("parser: unknown feed format %q", start.Name.Local)
+	}
+}
+
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\n', '\r':
+			if _, err := r.Discard(1); err != nil {
+				return 0, err
+			}
+		default:
+			return b[0], nil
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFeed(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantTitle string
+	}{
+		{
+			name: "rss 2.0",
+			input: `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>RSS Blog</title></channel></rss>`,
+			wantTitle: "RSS Blog",
+		},
+		{
+			name: "rss 1.0 / rdf",
+			input: `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"><channel><title>RDF Blog</title></channel></rdf:RDF>`,
+			wantTitle: "RDF Blog",
+		},
+		{
+			name: "atom 1.0",
+			input: `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>Atom Blog</title></feed>`,
+			wantTitle: "Atom Blog",
+		},
+		{
+			name: "atom 0.3",
+			input: `<?xml version="1.0"?>
+<feed version="0.3" xmlns="http://purl.org/atom/ns#"><title>Old Atom Blog</title></feed>`,
+			wantTitle: "Old Atom Blog",
+		},
+		{
+			name:      "json feed",
+			input:     `{"title": "JSON Blog", "items": []}`,
+			wantTitle: "JSON Blog",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFeed(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseFeed() error = %v", err)
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", got.Title, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestParseFeedUnknownFormat(t *testing.T) {
+	_, err := ParseFeed(strings.NewReader(`<?xml version="1.0"?><unknown/>`))
+	if err == nil {
+		t.Fatal("ParseFeed() error = nil, want an error for an unrecognized root element")
+	}
+}
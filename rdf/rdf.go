@@ -0,0 +1,58 @@
+// Package rdf parses RSS 1.0 feeds, which wrap a channel and its items in an
+// RDF document (http://www.w3.org/1999/02/22-rdf-syntax-ns#) instead of
+// nesting items under the channel the way RSS 2.0 does.
+package rdf
+
+import (
+	"encoding/xml"
+
+	"reader/date"
+	"reader/feed"
+	"reader/sanitizer"
+)
+
+type channel struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+}
+
+type item struct {
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+	Date  string `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+type rdfFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# RDF"`
+	Channel channel  `xml:"channel"`
+	Items   []item   `xml:"item"`
+}
+
+// Parse decodes an RSS 1.0/RDF document already positioned at its opening
+// <rdf:RDF> token and normalizes it into the shared feed model.
+func Parse(decoder *xml.Decoder, start xml.StartElement) (*feed.Feed, error) {
+	var parsed rdfFeed
+	if err := decoder.DecodeElement(&parsed, &start); err != nil {
+		return nil, err
+	}
+
+	var result feed.Feed
+	result.Title = parsed.Channel.Title
+	result.Description = parsed.Channel.Description
+	result.Link = parsed.Channel.Link
+	result.Id = result.Link
+
+	for _, it := range parsed.Items {
+		var entry feed.Entry
+		entry.Title = sanitizer.PlainText(it.Title)
+		entry.Link = it.Link
+		entry.Id = it.Link
+		entry.Published = date.ParseOrNow(it.Date)
+		entry.Updated = entry.Published
+
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,54 @@
+package rdf
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:dc="http://purl.org/dc/elements/1.1/">
+	<channel>
+		<title>Example Blog</title>
+		<link>https://example.com/</link>
+		<description>An example blog</description>
+	</channel>
+	<item>
+		<title>First post</title>
+		<link>https://example.com/first</link>
+		<dc:date>2006-01-02T15:04:05Z</dc:date>
+	</item>
+</rdf:RDF>`
+
+	decoder := xml.NewDecoder(strings.NewReader(input))
+
+	var start xml.StartElement
+	for start.Name.Local == "" {
+		token, err := decoder.Token()
+		if err != nil {
+			t.Fatalf("decoder.Token() error = %v", err)
+		}
+		if tok, ok := token.(xml.StartElement); ok {
+			start = tok
+		}
+	}
+
+	got, err := Parse(decoder, start)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got.Title != "Example Blog" {
+		t.Errorf("Title = %q, want %q", got.Title, "Example Blog")
+	}
+	if got.Link != "https://example.com/" {
+		t.Errorf("Link = %q, want %q", got.Link, "https://example.com/")
+	}
+	if len(got.Entries) != 1 {
+		t.Fatalf("len(Entries) = %d, want 1", len(got.Entries))
+	}
+	if got.Entries[0].Title != "First post" {
+		t.Errorf("Entries[0].Title = %q, want %q", got.Entries[0].Title, "First post")
+	}
+}
@@ -0,0 +1,213 @@
+// Package rss parses RSS 2.0 (and the 0.9x family, which differs only in
+// minor details) into the shared feed model.
+package rss
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"reader/date"
+	"reader/feed"
+	"reader/sanitizer"
+)
+
+// link covers both a plain <link>text</link> element and the Atom
+// <atom:link href="..." rel="..."/> form that shows up inside RSS feeds; the
+// tag deliberately omits a namespace so it matches either, since it's a
+// single field that sees both shapes sequentially (see selfLink/alternateLink).
+type link struct {
+	Href     string `xml:"href,attr"`
+	Rel      string `xml:"rel,attr"`
+	Chardata string `xml:",chardata"`
+}
+
+type mediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+type mediaContent struct {
+	URL       string `xml:"url,attr"`
+	Type      string `xml:"type,attr"`
+	Medium    string `xml:"medium,attr"`
+	Width     int    `xml:"width,attr"`
+	Height    int    `xml:"height,attr"`
+	Duration  int    `xml:"duration,attr"`
+	IsDefault string `xml:"isDefault,attr"`
+
+	Thumbnails []mediaThumbnail `xml:"thumbnail"`
+}
+
+type mediaGroup struct {
+	Contents   []mediaContent   `xml:"content"`
+	Thumbnails []mediaThumbnail `xml:"thumbnail"`
+}
+
+type item struct {
+	XMLName xml.Name `xml:"item"`
+	Title   string   `xml:"title"`
+	Links   []link   `xml:"link"`
+	Guid    string   `xml:"guid"`
+	PubDate string   `xml:"pubDate"`
+
+	DublinCoreCreator string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	DublinCoreDate    string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	ContentEncoded    string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+
+	MediaGroup      mediaGroup       `xml:"http://search.yahoo.com/mrss/ group"`
+	MediaContents   []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type channel struct {
+	Title         string `xml:"title"`
+	Description   string `xml:"description"`
+	LastBuildDate string `xml:"lastBuildDate"`
+	Links         []link `xml:"link"`
+	Items         []item `xml:"item"`
+}
+
+// rssFeed declares the default (un-prefixed) namespace on its XMLName, which
+// is how encoding/xml wants namespaces expressed on the document root; every
+// namespaced child element below is then matched with a fully-qualified
+// "namespace local" tag (see miniflux's RSS parser for the same approach).
+type rssFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Channel channel  `xml:"channel"`
+}
+
+// Parse decodes an RSS 2.0 (or 0.9x) document already positioned at its
+// opening token and normalizes it into the shared feed model.
+func Parse(decoder *xml.Decoder, start xml.StartElement) (*feed.Feed, error) {
+	var parsed rssFeed
+	if err := decoder.DecodeElement(&parsed, &start); err != nil {
+		return nil, err
+	}
+
+	channel := parsed.Channel
+
+	var result feed.Feed
+	result.Title = channel.Title
+	result.Description = channel.Description
+	result.Updated = date.ParseOrNow(channel.LastBuildDate)
+	result.Link = selfLink(channel.Links)
+	if result.Link == "" && len(channel.Links) > 0 {
+		result.Link = channel.Links[0].Chardata
+	}
+	result.Id = result.Link
+
+	for _, it := range channel.Items {
+		var entry feed.Entry
+		entry.Title = sanitizer.PlainText(it.Title)
+		entry.Link = plainLink(it.Links)
+		if entry.Link == "" {
+			entry.Link = alternateLink(it.Links)
+		}
+		if it.Guid != "" {
+			entry.Id = it.Guid
+		} else {
+			entry.Id = entry.Link
+		}
+
+		if it.ContentEncoded != "" {
+			entry.Content = sanitizer.Sanitize(feed.EntryBase(entry.Link, result.Link), it.ContentEncoded)
+		}
+
+		if it.DublinCoreCreator != "" {
+			entry.Authors = []string{it.DublinCoreCreator}
+		}
+
+		pubDate := it.PubDate
+		if pubDate == "" {
+			pubDate = it.DublinCoreDate
+		}
+		entry.Published = date.ParseOrNow(pubDate)
+		entry.Updated = entry.Published
+
+		entry.Media = mediaItems(it)
+
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return &result, nil
+}
+
+// selfLink returns the href of the first atom:link with rel="self".
+func selfLink(links []link) string {
+	for _, l := range links {
+		if l.Rel == "self" {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// plainLink returns the text content of the first bare <link> element.
+func plainLink(links []link) string {
+	for _, l := range links {
+		if l.Chardata != "" {
+			return l.Chardata
+		}
+	}
+	return ""
+}
+
+// alternateLink returns the href of the first atom:link with rel="alternate"
+// (or no rel at all, which defaults to "alternate" per the Atom spec), used
+// as a fallback when an item has no bare <link>.
+func alternateLink(links []link) string {
+	for _, l := range links {
+		if l.Href != "" && (l.Rel == "alternate" || l.Rel == "") {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// mediaItems flattens an item's Media RSS content, whether it was declared
+// directly under <item> or nested inside a <media:group>.
+func mediaItems(it item) []feed.Media {
+	contents := append(append([]mediaContent{}, it.MediaContents...), it.MediaGroup.Contents...)
+	thumbnails := append(append([]mediaThumbnail{}, it.MediaThumbnails...), it.MediaGroup.Thumbnails...)
+
+	var media []feed.Media
+	for _, c := range contents {
+		media = append(media, feed.Media{
+			URL:       c.URL,
+			Type:      c.Type,
+			Medium:    c.Medium,
+			Width:     c.Width,
+			Height:    c.Height,
+			Duration:  time.Duration(c.Duration) * time.Second,
+			IsDefault: c.IsDefault == "true",
+		})
+	}
+	for _, th := range thumbnails {
+		media = append(media, feed.Media{
+			URL:         th.URL,
+			Medium:      "image",
+			IsThumbnail: true,
+		})
+	}
+
+	return media
+}
+
+// ParseReader is a convenience wrapper around Parse for callers that have not
+// already located the root <rss> element.
+func ParseReader(r io.Reader) (*feed.Feed, error) {
+	decoder := xml.NewDecoder(r)
+
+	var start xml.StartElement
+	for start.Name.Local == "" {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := token.(xml.StartElement); ok {
+			start = t
+		}
+	}
+
+	return Parse(decoder, start)
+}
@@ -0,0 +1,161 @@
+package rss
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseReader(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantTitle string
+		wantLink  string
+		wantCount int
+	}{
+		{
+			name: "basic channel with items",
+			input: `<?xml version="1.0"?>
+<rss version="2.0">
+	<channel>
+		<title>Example Blog</title>
+		<description>An example blog</description>
+		<link>https://example.com/</link>
+		<item>
+			<title>First post</title>
+			<link>https://example.com/first</link>
+			<guid>https://example.com/first</guid>
+			<pubDate>Mon, 02 Jan 2006 15:04:05 MST</pubDate>
+		</item>
+	</channel>
+</rss>`,
+			wantTitle: "Example Blog",
+			wantLink:  "https://example.com/",
+			wantCount: 1,
+		},
+		{
+			name: "self link takes precedence",
+			input: `<?xml version="1.0"?>
+<rss version="2.0">
+	<channel>
+		<title>Example Blog</title>
+		<link>https://example.com/</link>
+		<link rel="self" href="https://example.com/feed.xml"/>
+	</channel>
+</rss>`,
+			wantTitle: "Example Blog",
+			wantLink:  "https://example.com/feed.xml",
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReader(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseReader() error = %v", err)
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			if got.Link != tt.wantLink {
+				t.Errorf("Link = %q, want %q", got.Link, tt.wantLink)
+			}
+			if len(got.Entries) != tt.wantCount {
+				t.Errorf("len(Entries) = %d, want %d", len(got.Entries), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestParseReaderNamespacedElements(t *testing.T) {
+	input := `<?xml version="1.0"?>
+<rss version="2.0"
+	xmlns:atom="http://www.w3.org/2005/Atom"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:media="http://search.yahoo.com/mrss/">
+	<channel>
+		<title>Example Blog</title>
+		<atom:link rel="self" href="https://example.com/feed.xml"/>
+		<item>
+			<title>First post</title>
+			<dc:creator>Jane Doe</dc:creator>
+			<dc:date>2006-01-02T15:04:05Z</dc:date>
+			<content:encoded><![CDATA[<p>Hello, world!</p>]]></content:encoded>
+			<atom:link rel="alternate" href="https://example.com/first"/>
+			<media:content url="https://example.com/first.mp3" type="audio/mpeg" medium="audio" duration="120"/>
+			<media:thumbnail url="https://example.com/first.jpg"/>
+		</item>
+	</channel>
+</rss>`
+
+	got, err := ParseReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+
+	if got.Link != "https://example.com/feed.xml" {
+		t.Errorf("Link = %q, want the atom:link rel=self href", got.Link)
+	}
+
+	if len(got.Entries) != 1 {
+		t.Fatalf("len(Entries) = %d, want 1", len(got.Entries))
+	}
+	entry := got.Entries[0]
+
+	if entry.Link != "https://example.com/first" {
+		t.Errorf("Link = %q, want the atom:link rel=alternate href", entry.Link)
+	}
+	if entry.Content != "<p>Hello, world!</p>" {
+		t.Errorf("Content = %q, want the content:encoded body", entry.Content)
+	}
+	if len(entry.Authors) != 1 || entry.Authors[0] != "Jane Doe" {
+		t.Errorf("Authors = %v, want [Jane Doe]", entry.Authors)
+	}
+	if entry.Published.IsZero() {
+		t.Error("Published is zero, want the dc:date fallback to be used")
+	}
+	if len(entry.Media) != 2 {
+		t.Fatalf("len(Media) = %d, want 2", len(entry.Media))
+	}
+	if entry.Media[0].URL != "https://example.com/first.mp3" || entry.Media[0].Duration != 120*time.Second {
+		t.Errorf("Media[0] = %+v, want the media:content entry", entry.Media[0])
+	}
+	if !entry.Media[1].IsThumbnail || entry.Media[1].URL != "https://example.com/first.jpg" {
+		t.Errorf("Media[1] = %+v, want the media:thumbnail entry", entry.Media[1])
+	}
+}
+
+func TestParseReaderSanitizesTitleAndContent(t *testing.T) {
+	input := `<?xml version="1.0"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+	<channel>
+		<title>Example Blog</title>
+		<item>
+			<title>&lt;b&gt;First&lt;/b&gt; post</title>
+			<link>https://example.com/blog/first</link>
+			<content:encoded><![CDATA[<p>hi</p><script>alert(1)</script><a href="/about">about</a>]]></content:encoded>
+		</item>
+	</channel>
+</rss>`
+
+	got, err := ParseReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+	if len(got.Entries) != 1 {
+		t.Fatalf("len(Entries) = %d, want 1", len(got.Entries))
+	}
+
+	entry := got.Entries[0]
+	if entry.Title != "First post" {
+		t.Errorf("Title = %q, want the markup stripped", entry.Title)
+	}
+
+	want := `<p>hi</p><a href="https://example.com/about">about</a>`
+	if entry.Content != want {
+		t.Errorf("Content = %q, want %q", entry.Content, want)
+	}
+}
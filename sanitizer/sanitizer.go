@@ -0,0 +1,244 @@
+// Package sanitizer cleans untrusted HTML found in feed entries: it drops
+// scripts, event handlers, and javascript: URLs, strips any tag not on a
+// small allowlist (keeping its text), and rewrites relative href/src values
+// to absolute URLs.
+package sanitizer
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedTags maps each tag we keep to the attributes we keep on it. Any tag
+// not listed here is unwrapped: the tag is dropped but its children are kept
+// in its place.
+var allowedTags = map[string]map[string]bool{
+	"a":          {"href": true, "title": true},
+	"p":          {},
+	"br":         {},
+	"hr":         {},
+	"div":        {},
+	"span":       {},
+	"b":          {},
+	"strong":     {},
+	"i":          {},
+	"em":         {},
+	"u":          {},
+	"s":          {},
+	"strike":     {},
+	"del":        {},
+	"ins":        {},
+	"blockquote": {},
+	"pre":        {},
+	"code":       {},
+	"kbd":        {},
+	"samp":       {},
+	"sub":        {},
+	"sup":        {},
+	"h1":         {},
+	"h2":         {},
+	"h3":         {},
+	"h4":         {},
+	"h5":         {},
+	"h6":         {},
+	"ul":         {},
+	"ol":         {},
+	"li":         {},
+	"dl":         {},
+	"dt":         {},
+	"dd":         {},
+	"table":      {},
+	"thead":      {},
+	"tbody":      {},
+	"tfoot":      {},
+	"tr":         {},
+	"td":         {"colspan": true, "rowspan": true},
+	"th":         {"colspan": true, "rowspan": true},
+	"figure":     {},
+	"figcaption": {},
+	"img":        {"src": true, "alt": true, "title": true, "width": true, "height": true},
+}
+
+// removedTags are dropped along with their children and text content,
+// instead of being unwrapped, since that content is never meant to be
+// rendered (code) or has historically been a reliable XSS vector.
+var removedTags = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"iframe":   true,
+	"object":   true,
+	"embed":    true,
+	"noscript": true,
+	"form":     true,
+}
+
+// urlAttrs are the attributes treated as URLs: checked against a scheme
+// allowlist and, if relative, resolved against the current base URL.
+var urlAttrs = map[string]bool{"href": true, "src": true}
+
+// Sanitize cleans html, keeping only the allowed tags/attributes and
+// resolving relative href/src values against baseURL. An element may
+// override the base URL for itself and its descendants with an xml:base
+// attribute.
+func Sanitize(baseURL, rawHTML string) string {
+	base, _ := url.Parse(baseURL)
+
+	nodes, err := html.ParseFragment(strings.NewReader(rawHTML), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return ""
+	}
+
+	root := &html.Node{Type: html.DocumentNode}
+	for _, n := range nodes {
+		appendSanitized(root, n, base)
+	}
+
+	var buf bytes.Buffer
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return ""
+		}
+	}
+
+	return buf.String()
+}
+
+// PlainText strips all markup from html and returns just the text content,
+// suitable for fields like a feed entry's title that should never contain
+// markup even though the underlying format technically allows it.
+func PlainText(rawHTML string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(rawHTML), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return rawHTML
+	}
+
+	var buf strings.Builder
+	for _, n := range nodes {
+		writeText(&buf, n)
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+func writeText(buf *strings.Builder, n *html.Node) {
+	if n.Type == html.TextNode {
+		buf.WriteString(n.Data)
+	}
+	if n.Type == html.ElementNode && removedTags[strings.ToLower(n.Data)] {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeText(buf, c)
+	}
+}
+
+// appendSanitized copies n into dst's children, dropping or unwrapping
+// disallowed tags and filtering attributes, then recurses into n's children.
+func appendSanitized(dst *html.Node, n *html.Node, base *url.URL) {
+	switch n.Type {
+	case html.TextNode:
+		dst.AppendChild(&html.Node{Type: html.TextNode, Data: n.Data})
+		return
+	case html.CommentNode, html.DoctypeNode:
+		return
+	case html.ElementNode:
+		// fall through below
+	default:
+		return
+	}
+
+	tag := strings.ToLower(n.Data)
+
+	if removedTags[tag] {
+		return
+	}
+
+	if xmlBase, ok := attr(n, "xml:base"); ok {
+		if resolved, err := base.Parse(xmlBase); err == nil {
+			base = resolved
+		}
+	}
+
+	allowedAttrs, ok := allowedTags[tag]
+	if !ok {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			appendSanitized(dst, c, base)
+		}
+		return
+	}
+
+	clean := &html.Node{Type: html.ElementNode, Data: tag, DataAtom: n.DataAtom}
+	for _, a := range n.Attr {
+		key := strings.ToLower(a.Key)
+		if !allowedAttrs[key] {
+			continue
+		}
+
+		val := a.Val
+		if urlAttrs[key] {
+			if isUnsafeURL(val) {
+				continue
+			}
+			val = resolve(base, val)
+		}
+
+		clean.Attr = append(clean.Attr, html.Attribute{Key: key, Val: val})
+	}
+	dst.AppendChild(clean)
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		appendSanitized(clean, c, base)
+	}
+}
+
+func attr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// isUnsafeURL rejects javascript:, data:, and vbscript: URLs, the classic
+// script-injection vectors for href/src attributes.
+func isUnsafeURL(raw string) bool {
+	trimmed := strings.ToLower(strings.TrimSpace(raw))
+	trimmed = strings.Map(func(r rune) rune {
+		switch r {
+		case '\t', '\n', '\r':
+			return -1
+		default:
+			return r
+		}
+	}, trimmed)
+
+	return strings.HasPrefix(trimmed, "javascript:") ||
+		strings.HasPrefix(trimmed, "vbscript:") ||
+		strings.HasPrefix(trimmed, "data:")
+}
+
+func resolve(base *url.URL, href string) string {
+	if base == nil {
+		return href
+	}
+
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+
+	return base.ResolveReference(ref).String()
+}
@@ -0,0 +1,117 @@
+package sanitizer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		input   string
+		want    string
+	}{
+		{
+			name:    "strips script tags and their content",
+			baseURL: "https://example.com/",
+			input:   `<p>hello</p><script>alert(1)</script>`,
+			want:    `<p>hello</p>`,
+		},
+		{
+			name:    "strips event handler attributes",
+			baseURL: "https://example.com/",
+			input:   `<p onclick="alert(1)">hello</p>`,
+			want:    `<p>hello</p>`,
+		},
+		{
+			name:    "strips javascript: URLs",
+			baseURL: "https://example.com/",
+			input:   `<a href="javascript:alert(1)">click</a>`,
+			want:    `<a>click</a>`,
+		},
+		{
+			name:    "strips data: URLs",
+			baseURL: "https://example.com/",
+			input:   `<img src="data:text/html,<script>alert(1)</script>">`,
+			want:    `<img/>`,
+		},
+		{
+			name:    "unwraps unknown tags but keeps their content",
+			baseURL: "https://example.com/",
+			input:   `<marquee>hello</marquee>`,
+			want:    `hello`,
+		},
+		{
+			name:    "rewrites a relative href to absolute",
+			baseURL: "https://example.com/blog/post",
+			input:   `<a href="/about">about</a>`,
+			want:    `<a href="https://example.com/about">about</a>`,
+		},
+		{
+			name:    "rewrites a relative img src to absolute",
+			baseURL: "https://example.com/blog/post",
+			input:   `<img src="../images/cat.png">`,
+			want:    `<img src="https://example.com/images/cat.png"/>`,
+		},
+		{
+			name:    "leaves an absolute href untouched",
+			baseURL: "https://example.com/",
+			input:   `<a href="https://other.example.com/page">page</a>`,
+			want:    `<a href="https://other.example.com/page">page</a>`,
+		},
+		{
+			name:    "keeps allowed formatting tags",
+			baseURL: "https://example.com/",
+			input:   `<p>Some <strong>bold</strong> and <em>italic</em> text.</p>`,
+			want:    `<p>Some <strong>bold</strong> and <em>italic</em> text.</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sanitize(tt.baseURL, tt.input)
+			if got != tt.want {
+				t.Errorf("Sanitize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeXMLBase(t *testing.T) {
+	input := `<div xml:base="https://other.example.com/docs/"><a href="page.html">link</a></div>`
+	got := Sanitize("https://example.com/", input)
+	want := `<div><a href="https://other.example.com/docs/page.html">link</a></div>`
+	if got != want {
+		t.Errorf("Sanitize() = %q, want %q", got, want)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain text is untouched", input: "Hello, world!", want: "Hello, world!"},
+		{name: "tags are stripped", input: "<b>Hello</b>, <i>world</i>!", want: "Hello, world!"},
+		{name: "script content is dropped", input: "Hello<script>alert(1)</script>", want: "Hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlainText(tt.input)
+			if got != tt.want {
+				t.Errorf("PlainText(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeDropsUnsafeSchemesCaseInsensitively(t *testing.T) {
+	input := `<a href="JaVaScRiPt:alert(1)">click</a>`
+	got := Sanitize("https://example.com/", input)
+	if strings.Contains(got, "JaVaScRiPt") || strings.Contains(strings.ToLower(got), "javascript:") {
+		t.Errorf("Sanitize() = %q, want the javascript: URL stripped", got)
+	}
+}